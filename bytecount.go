@@ -0,0 +1,54 @@
+package httplog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// requestBodyCounter wraps r.Body so BytesInField can reflect bytes actually read off the wire instead of just the
+// Content-Length header, which is frequently absent for chunked uploads. It atomically increments on every Read so
+// it stays negligible under load.
+type requestBodyCounter struct {
+	io.ReadCloser
+	read int64
+}
+
+// Read implements io.Reader.
+func (c *requestBodyCounter) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	atomic.AddInt64(&c.read, int64(n))
+	return n, err
+}
+
+// Bytes returns the number of bytes read so far.
+func (c *requestBodyCounter) Bytes() int64 {
+	return atomic.LoadInt64(&c.read)
+}
+
+type requestBodyCounterKey struct{}
+
+// withRequestBodyCounter returns a copy of ctx carrying c, retrievable via requestBodyCounterFromContext.
+func withRequestBodyCounter(ctx context.Context, c *requestBodyCounter) context.Context {
+	return context.WithValue(ctx, requestBodyCounterKey{}, c)
+}
+
+// requestBodyCounterFromContext returns the requestBodyCounter RegisterMiddleware installed, or nil if ctx carries
+// none (e.g. r.Body was never swapped).
+func requestBodyCounterFromContext(ctx context.Context) *requestBodyCounter {
+	c, _ := ctx.Value(requestBodyCounterKey{}).(*requestBodyCounter)
+	return c
+}
+
+// approxHeaderBytes estimates the number of bytes the status line and response headers occupy on the wire.
+func approxHeaderBytes(code int, h http.Header) int64 {
+	total := int64(len(fmt.Sprintf("HTTP/1.1 %d %s\r\n", code, http.StatusText(code))))
+	for k, vs := range h {
+		for _, v := range vs {
+			total += int64(len(k) + len(": ") + len(v) + len("\r\n"))
+		}
+	}
+	return total + int64(len("\r\n"))
+}
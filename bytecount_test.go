@@ -0,0 +1,38 @@
+package httplog
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodyCounter_CountsActualBytesRead(t *testing.T) {
+	c := &requestBodyCounter{ReadCloser: ioNopCloser{strings.NewReader("hello world")}}
+
+	buf := make([]byte, 5)
+	n, err := c.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, int64(5), c.Bytes())
+
+	n, err = c.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, int64(10), c.Bytes())
+}
+
+func TestApproxHeaderBytes_AccountsForStatusLineAndHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+
+	got := approxHeaderBytes(http.StatusOK, h)
+	require.True(t, got > int64(len("Content-Type: application/json")))
+}
+
+type ioNopCloser struct {
+	*strings.Reader
+}
+
+func (ioNopCloser) Close() error { return nil }
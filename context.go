@@ -0,0 +1,126 @@
+package httplog
+
+import (
+	"context"
+	"sync"
+)
+
+type contextKey int
+
+const (
+	loggerContextKey contextKey = iota
+	responseFieldsContextKey
+	deferredRequestLogContextKey
+)
+
+// discardLogger is the FieldLogger returned by FromContext when no logger was installed, so callers can use it
+// unconditionally without nil checks.
+type discardLogger struct{}
+
+func (discardLogger) WithFields(Fields) FieldLogger { return discardLogger{} }
+func (discardLogger) Log(args ...interface{})       {}
+
+// NewContext returns a copy of ctx carrying logger, retrievable via FromContext:
+//
+//	httplog.FromContext(r.Context()).WithFields(httplog.Fields{"user_id": id}).Log("loaded user")
+func NewContext(ctx context.Context, logger FieldLogger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// FromContext returns the FieldLogger installed by NewContext, or a no-op logger if ctx carries none.
+func FromContext(ctx context.Context) FieldLogger {
+	if logger, ok := ctx.Value(loggerContextKey).(FieldLogger); ok {
+		return logger
+	}
+	return discardLogger{}
+}
+
+// responseFields accumulates Fields added via AddResponseFields for the lifetime of a single request.
+type responseFields struct {
+	mu     sync.Mutex
+	fields Fields
+}
+
+// newResponseFieldsContext returns a copy of ctx ready to accumulate fields added via AddResponseFields.
+// RegisterMiddleware installs this on every request.
+func newResponseFieldsContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseFieldsContextKey, &responseFields{fields: Fields{}})
+}
+
+// AddResponseFields appends fields to be merged into the "Responding to HTTP request" (or redirect) entry that
+// responseLogger.log emits once the handler finishes. It is a no-op if ctx was not produced by RegisterMiddleware.
+func AddResponseFields(ctx context.Context, fields Fields) {
+	rf, ok := ctx.Value(responseFieldsContextKey).(*responseFields)
+	if !ok {
+		return
+	}
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	for k, v := range fields {
+		rf.fields[k] = v
+	}
+}
+
+// responseFieldsFromContext returns a snapshot copy of the fields accumulated via AddResponseFields, or nil if ctx
+// carries none.
+func responseFieldsFromContext(ctx context.Context) Fields {
+	rf, ok := ctx.Value(responseFieldsContextKey).(*responseFields)
+	if !ok {
+		return nil
+	}
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	cp := make(Fields, len(rf.fields))
+	for k, v := range rf.fields {
+		cp[k] = v
+	}
+	return cp
+}
+
+// deferredRequestLog holds the "Received HTTP request" entry RequestHandler buffers instead of logging immediately,
+// until responseLogger.log knows whether it's worth keeping.
+type deferredRequestLog struct {
+	mu      sync.Mutex
+	pending bool
+	message string
+}
+
+// newDeferredRequestLogContext returns a copy of ctx ready to buffer a deferred request log entry.
+// RegisterMiddleware installs this on every request.
+func newDeferredRequestLogContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deferredRequestLogContextKey, &deferredRequestLog{})
+}
+
+// markDeferredRequestLog records message as pending; flushDeferredRequestLog logs it later if the request turns
+// out to be worth logging. It is a no-op if ctx was not produced by RegisterMiddleware.
+func markDeferredRequestLog(ctx context.Context, message string) {
+	d, ok := ctx.Value(deferredRequestLogContextKey).(*deferredRequestLog)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending = true
+	d.message = message
+}
+
+// flushDeferredRequestLog logs whatever markDeferredRequestLog recorded (if anything) via logger, then clears it
+// so it is never logged twice.
+func flushDeferredRequestLog(ctx context.Context, logger FieldLogger) {
+	d, ok := ctx.Value(deferredRequestLogContextKey).(*deferredRequestLog)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	pending, message := d.pending, d.message
+	d.pending = false
+	d.mu.Unlock()
+
+	if pending {
+		logger.Log(message)
+	}
+}
@@ -0,0 +1,37 @@
+package httplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_NoLoggerInstalled_ReturnsDiscardLogger(t *testing.T) {
+	logger := FromContext(context.Background())
+	require.NotNil(t, logger)
+
+	// Must not panic and must stay chainable, even though nothing is actually logged anywhere.
+	logger.WithFields(Fields{"a": "b"}).Log("something")
+}
+
+func TestNewContext_FromContext_RoundTrips(t *testing.T) {
+	mLogger := new(MockFieldLogger)
+	ctx := NewContext(context.Background(), mLogger)
+
+	require.Equal(t, FieldLogger(mLogger), FromContext(ctx))
+}
+
+func TestAddResponseFields_MergesAcrossCalls(t *testing.T) {
+	ctx := newResponseFieldsContext(context.Background())
+
+	AddResponseFields(ctx, Fields{"a": "1"})
+	AddResponseFields(ctx, Fields{"b": "2", "a": "overwritten"})
+
+	require.Equal(t, Fields{"a": "overwritten", "b": "2"}, responseFieldsFromContext(ctx))
+}
+
+func TestAddResponseFields_NoHolderInContext_NoOp(t *testing.T) {
+	// Must not panic when called against a context RegisterMiddleware never touched.
+	AddResponseFields(context.Background(), Fields{"a": "1"})
+}
@@ -5,18 +5,23 @@ import (
 	"github.com/labstack/echo"
 )
 
-// RegisterMiddleware registers echo handler that will log request at the beginning and served response at the request end.
+// RegisterMiddleware registers echo handler that will log request at the beginning and served response at the
+// request end. It installs the same request context plumbing as httplog.RegisterMiddleware, so handlers and
+// echolog.RecoveryMiddleware behave the same whether a service is wired through net/http or echo.
 func RegisterMiddleware(logger httplog.FieldLogger, cfg httplog.Config) echo.MiddlewareFunc {
 	l := httplog.New(logger, cfg)
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
+			r := l.PrepareRequest(c.Request())
+			c.SetRequest(r)
+
 			// Log specified RequestFields now.
-			l.RequestHandler()(c.Response(), c.Request())
+			l.RequestHandler()(c.Response(), r)
 
 			// Wrap ResponseWriter under echo.Response to log specified ResponseFields and ResponseReqFields on
 			// Response Write or Redirect.
-			w := l.WrapResponse(c.Response().Writer, c.Request())
+			w := l.WrapResponse(c.Response().Writer, r)
 			c.Response().Writer = w
 			return next(c)
 		}
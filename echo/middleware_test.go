@@ -0,0 +1,83 @@
+package echolog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bplotka/go-httplog"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger is a minimal httplog.FieldLogger double. It is defined locally instead of reusing
+// httplog.MockFieldLogger because that mock is only generated (go:generate mockery ...) inside the httplog
+// package itself and isn't importable from here.
+type recordingLogger struct {
+	fields []httplog.Fields
+}
+
+func (l *recordingLogger) WithFields(fields httplog.Fields) httplog.FieldLogger {
+	l.fields = append(l.fields, fields)
+	return l
+}
+
+func (l *recordingLogger) Log(args ...interface{}) {}
+
+func TestRegisterMiddleware_InstallsContextForFromContext(t *testing.T) {
+	logger := &recordingLogger{}
+	e := echo.New()
+
+	h := RegisterMiddleware(logger, httplog.DefaultReqResConfig())(func(c echo.Context) error {
+		require.Equal(t, httplog.FieldLogger(logger), httplog.FromContext(c.Request().Context()))
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/some_endpoint", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, h(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRegisterMiddleware_InstallsContextForAddResponseFields(t *testing.T) {
+	logger := &recordingLogger{}
+	e := echo.New()
+
+	h := RegisterMiddleware(logger, httplog.DefaultReqResConfig())(func(c echo.Context) error {
+		// Must not be a no-op: RegisterMiddleware has to install the response-fields accumulator before calling
+		// next, the same way httplog.RegisterMiddleware does for net/http.
+		httplog.AddResponseFields(c.Request().Context(), httplog.Fields{"custom": "value"})
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/some_endpoint", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, h(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "value", logger.fields[len(logger.fields)-1]["custom"])
+}
+
+func TestRecoveryMiddleware_AttachesPanicFieldsToRegisterMiddlewareEntry(t *testing.T) {
+	logger := &recordingLogger{}
+	e := echo.New()
+
+	h := RegisterMiddleware(logger, httplog.DefaultReqResConfig())(
+		RecoveryMiddleware(logger)(func(c echo.Context) error {
+			panic("boom")
+		}),
+	)
+
+	req := httptest.NewRequest("GET", "/some_endpoint", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NotPanics(t, func() {
+		_ = h(c)
+	})
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, "boom", logger.fields[len(logger.fields)-1][string(httplog.PanicField)])
+}
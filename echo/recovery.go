@@ -0,0 +1,40 @@
+package echolog
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/Bplotka/go-httplog"
+	"github.com/labstack/echo"
+)
+
+// RecoveryMiddleware recovers from panics raised by downstream handlers, attaching the panic value and stack trace
+// to the same log entry RegisterMiddleware produces for the request (via httplog.AddResponseFields) so operators
+// get one correlated log line per failed request instead of a separate stderr panic dump.
+func RecoveryMiddleware(logger httplog.FieldLogger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+
+				fields := httplog.Fields{
+					string(httplog.PanicField):      fmt.Sprintf("%v", rec),
+					string(httplog.PanicStackField): string(debug.Stack()),
+				}
+				httplog.AddResponseFields(c.Request().Context(), fields)
+
+				if c.Response().Committed {
+					logger.WithFields(fields).Log("recovered from panic after response already committed")
+					return
+				}
+
+				err = c.String(http.StatusInternalServerError, "Internal Server Error")
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Package emit provides Emitter, an output-agnostic sink for structured log entries, plus a few ready-to-use
+// implementations (NDJSON, logfmt, Fluentd forward protocol). It lets httplog.Logger stream directly to a file,
+// stdout or a log collector without pulling in a FieldLogger implementation such as logrus.
+package emit
+
+// Entry is a single structured log line ready to be handed to an Emitter.
+type Entry struct {
+	// Message is the human-readable log line, e.g. "Responding to HTTP request".
+	Message string
+	// Fields are the structured key/value pairs attached to Message.
+	Fields map[string]interface{}
+}
+
+// Emitter writes an Entry to some backend (a file, stdout, a remote log collector, ...).
+type Emitter interface {
+	Emit(entry Entry) error
+}
@@ -0,0 +1,27 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONEmitter_Emit(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewJSONEmitter(&buf)
+
+	require.NoError(t, e.Emit(Entry{Message: "hello", Fields: map[string]interface{}{"a": "b"}}))
+	require.NoError(t, e.Emit(Entry{Message: "world"}))
+
+	require.Equal(t, "{\"a\":\"b\",\"msg\":\"hello\"}\n{\"msg\":\"world\"}\n", buf.String())
+}
+
+func TestLogfmtEmitter_Emit(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewLogfmtEmitter(&buf)
+
+	require.NoError(t, e.Emit(Entry{Message: "hello world", Fields: map[string]interface{}{"b": 2, "a": "x y"}}))
+
+	require.Equal(t, "msg=\"hello world\" a=\"x y\" b=2\n", buf.String())
+}
@@ -0,0 +1,142 @@
+package emit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSinkConfig configures a FileSink.
+type FileSinkConfig struct {
+	// Path is the file the sink writes to. It is created if missing and appended to otherwise.
+	Path string
+	// BufferSize is the size in bytes of the buffered writer sitting in front of the file. 0 uses bufio's default.
+	BufferSize int
+	// MaxSizeBytes rotates the file once it grows past this size. 0 disables rotation.
+	MaxSizeBytes int64
+	// MaxBackups bounds how many rotated files (Path+".1", Path+".2", ...) are kept; older ones are removed.
+	// Ignored if MaxSizeBytes is 0.
+	MaxBackups int
+	// Format renders entry as a single line (including its own trailing newline). Defaults to a logfmt line.
+	Format func(entry Entry) []byte
+}
+
+// FileSink is an Emitter writing to a local file, with optional size-based rotation. It mirrors the rotating
+// request-log file sinks found in reverse proxies such as gofer's reqlog, for operators who want plain files
+// instead of (or in addition to) shipping to Fluentd.
+type FileSink struct {
+	cfg FileSinkConfig
+
+	mu   sync.Mutex
+	f    *os.File
+	w    *bufio.Writer
+	size int64
+}
+
+// NewFileSink opens cfg.Path (creating it if necessary) and returns a FileSink writing to it.
+func NewFileSink(cfg FileSinkConfig) (*FileSink, error) {
+	if cfg.Format == nil {
+		cfg.Format = defaultFileFormat
+	}
+
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	var w *bufio.Writer
+	if cfg.BufferSize > 0 {
+		w = bufio.NewWriterSize(f, cfg.BufferSize)
+	} else {
+		w = bufio.NewWriter(f)
+	}
+
+	return &FileSink{cfg: cfg, f: f, w: w, size: fi.Size()}, nil
+}
+
+func defaultFileFormat(entry Entry) []byte {
+	le := LogfmtEmitter{}
+	var written []byte
+	le.w = writerFunc(func(p []byte) (int, error) {
+		written = append(written, p...)
+		return len(p), nil
+	})
+	_ = le.Emit(entry)
+	return written
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// Emit implements Emitter.
+func (s *FileSink) Emit(entry Entry) error {
+	b := s.cfg.Format(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.MaxSizeBytes > 0 && s.size+int64(len(b)) > s.cfg.MaxSizeBytes {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.w.Write(b)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// rotateLocked must be called with s.mu held. It closes the current file, shifts Path+".N" backups up by one
+// (dropping anything past MaxBackups) and reopens a fresh, empty Path.
+func (s *FileSink) rotateLocked() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+
+	if s.cfg.MaxBackups > 0 {
+		oldest := fmt.Sprintf("%s.%d", s.cfg.Path, s.cfg.MaxBackups)
+		os.Remove(oldest)
+		for i := s.cfg.MaxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", s.cfg.Path, i), fmt.Sprintf("%s.%d", s.cfg.Path, i+1))
+		}
+		os.Rename(s.cfg.Path, s.cfg.Path+".1")
+	}
+
+	f, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	s.f = f
+	if s.cfg.BufferSize > 0 {
+		s.w = bufio.NewWriterSize(f, s.cfg.BufferSize)
+	} else {
+		s.w = bufio.NewWriter(f)
+	}
+	s.size = 0
+	return nil
+}
+
+// Close flushes buffered data and closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.f.Close()
+}
@@ -0,0 +1,36 @@
+package emit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_RotatesPastMaxSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emit-filesink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "req.log")
+	s, err := NewFileSink(FileSinkConfig{
+		Path:         path,
+		MaxSizeBytes: 20,
+		MaxBackups:   1,
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Emit(Entry{Message: "request handled"}))
+	}
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+	_, err = os.Stat(path + ".1")
+	require.NoError(t, err)
+	_, err = os.Stat(path + ".2")
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,164 @@
+package emit
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// FluentConfig configures a FluentEmitter.
+type FluentConfig struct {
+	// Tag is the Fluentd tag entries are forwarded under.
+	Tag string
+	// Addr is the fluentd/fluent-bit in_forward endpoint, e.g. "localhost:24224".
+	Addr string
+	// BufferSize is how many entries are batched before being flushed in one forward-protocol message.
+	// Defaults to 1 (flush on every Emit) if <= 0.
+	BufferSize int
+	// FlushInterval forces a flush of whatever is buffered even if BufferSize hasn't been reached.
+	// Defaults to 1s if <= 0.
+	FlushInterval time.Duration
+	// DialTimeout bounds how long (re)connecting to Addr may take. Defaults to 5s if <= 0.
+	DialTimeout time.Duration
+}
+
+type fluentRecord struct {
+	time   int64
+	fields map[string]interface{}
+}
+
+// FluentEmitter batches Entries and writes them to a Fluentd in_forward listener in MessagePack forward protocol,
+// i.e. [tag, [[time, record], [time, record], ...]]. It reconnects lazily on write failure so a momentary outage
+// of the collector doesn't take the emitting process down with it.
+type FluentEmitter struct {
+	cfg FluentConfig
+
+	mu      sync.Mutex
+	conn    net.Conn
+	pending []fluentRecord
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// NewFluentEmitter constructs a FluentEmitter and starts its background flush loop. Call Close to stop it and
+// flush whatever remains buffered.
+func NewFluentEmitter(cfg FluentConfig) *FluentEmitter {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+
+	e := &FluentEmitter{cfg: cfg, closeCh: make(chan struct{})}
+	go e.flushLoop()
+	return e
+}
+
+// Emit implements Emitter. Entries are buffered and flushed by the background loop; once BufferSize is reached,
+// Emit flushes synchronously instead, (re)dialing and writing to Addr and returning any error from that flush.
+func (e *FluentEmitter) Emit(entry Entry) error {
+	rec := make(map[string]interface{}, len(entry.Fields)+1)
+	for k, v := range entry.Fields {
+		rec[k] = v
+	}
+	rec["msg"] = entry.Message
+
+	e.mu.Lock()
+	e.pending = append(e.pending, fluentRecord{time: timeNowUnix(), fields: rec})
+	flush := len(e.pending) >= e.cfg.BufferSize
+	e.mu.Unlock()
+
+	if flush {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *FluentEmitter) flushLoop() {
+	t := time.NewTicker(e.cfg.FlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			_ = e.flush()
+		case <-e.closeCh:
+			return
+		}
+	}
+}
+
+func (e *FluentEmitter) flush() error {
+	e.mu.Lock()
+	batch := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	b, err := encodeForwardMessage(e.cfg.Tag, batch)
+	if err != nil {
+		return err
+	}
+
+	conn, err := e.connection()
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.Write(b); err != nil {
+		e.mu.Lock()
+		if e.conn == conn {
+			e.conn.Close()
+			e.conn = nil
+		}
+		e.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// connection returns the current connection, (re)dialing e.cfg.Addr if none is established.
+func (e *FluentEmitter) connection() (net.Conn, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		return e.conn, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", e.cfg.Addr, e.cfg.DialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("emit: dialing fluentd at %s: %s", e.cfg.Addr, err)
+	}
+	e.conn = conn
+	return conn, nil
+}
+
+// Close stops the background flush loop, flushes whatever is buffered and closes the connection.
+func (e *FluentEmitter) Close() error {
+	e.closeOnce.Do(func() { close(e.closeCh) })
+
+	err := e.flush()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn != nil {
+		if cerr := e.conn.Close(); err == nil {
+			err = cerr
+		}
+		e.conn = nil
+	}
+	return err
+}
+
+// timeNowUnix is a var so tests can stub it, matching the timeNow convention used by the parent httplog package.
+var timeNowUnix = func() int64 { return time.Now().Unix() }
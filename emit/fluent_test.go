@@ -0,0 +1,279 @@
+package emit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFluentEmitter_EmitWritesDecodableForwardMessage(t *testing.T) {
+	timeNowUnix = func() int64 { return 1234 }
+	defer func() { timeNowUnix = func() int64 { return time.Now().Unix() } }()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		b, _ := ioutil.ReadAll(conn)
+		received <- b
+	}()
+
+	e := NewFluentEmitter(FluentConfig{
+		Tag:        "test.tag",
+		Addr:       ln.Addr().String(),
+		BufferSize: 1,
+	})
+
+	require.NoError(t, e.Emit(Entry{Message: "hello", Fields: map[string]interface{}{"k": "v"}}))
+	require.NoError(t, e.Close())
+
+	var b []byte
+	select {
+	case b = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the forwarded message")
+	}
+
+	tag, entries, err := decodeForwardMessage(b)
+	require.NoError(t, err)
+	require.Equal(t, "test.tag", tag)
+	require.Len(t, entries, 1)
+	require.Equal(t, int64(1234), entries[0].time)
+	require.Equal(t, "hello", entries[0].record["msg"])
+	require.Equal(t, "v", entries[0].record["k"])
+}
+
+func TestFluentEmitter_ReconnectsAfterWriteFailure(t *testing.T) {
+	timeNowUnix = func() int64 { return 1234 }
+	defer func() { timeNowUnix = func() int64 { return time.Now().Unix() } }()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	accepted := make(chan []byte, 1)
+	go func() {
+		for i := 0; i < 2; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			b, _ := ioutil.ReadAll(conn)
+			accepted <- b
+		}
+	}()
+
+	e := NewFluentEmitter(FluentConfig{
+		Tag:        "test.tag",
+		Addr:       ln.Addr().String(),
+		BufferSize: 1,
+	})
+
+	// Simulate a connection gone stale between flushes (e.g. the collector dropped it): dial and close it
+	// ourselves, then install it as e's current connection so the next flush's Write fails.
+	stale, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, stale.Close())
+	<-accepted // drain the Accept() the stale dial produced.
+
+	e.mu.Lock()
+	e.conn = stale
+	e.mu.Unlock()
+
+	err = e.Emit(Entry{Message: "first"})
+	require.Error(t, err, "flush over the stale connection must fail")
+
+	// The failed flush must have dropped the stale connection so the next one redials instead of erroring forever.
+	require.NoError(t, e.Emit(Entry{Message: "second"}))
+	require.NoError(t, e.Close()) // closes the reconnected conn, so the server's ReadAll below sees EOF.
+
+	var b []byte
+	select {
+	case b = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the reconnected message")
+	}
+
+	tag, entries, err := decodeForwardMessage(b)
+	require.NoError(t, err)
+	require.Equal(t, "test.tag", tag)
+	require.Len(t, entries, 1)
+	require.Equal(t, "second", entries[0].record["msg"])
+}
+
+// decodedEntry and decodeForwardMessage are a minimal counterpart to appendArrayHeader/appendString/appendMap in
+// msgpack.go -- just enough to decode what encodeForwardMessage ever produces (strings, int64s, a flat map) back
+// into Go values for assertions.
+type decodedEntry struct {
+	time   int64
+	record map[string]interface{}
+}
+
+func decodeForwardMessage(b []byte) (string, []decodedEntry, error) {
+	d := &decoder{b: b}
+
+	n, err := d.arrayHeader()
+	if err != nil || n != 2 {
+		return "", nil, fmt.Errorf("emit: expected 2-element forward message array, got %d (%v)", n, err)
+	}
+
+	tag, err := d.str()
+	if err != nil {
+		return "", nil, err
+	}
+
+	batchLen, err := d.arrayHeader()
+	if err != nil {
+		return "", nil, err
+	}
+
+	entries := make([]decodedEntry, 0, batchLen)
+	for i := 0; i < batchLen; i++ {
+		if n, err := d.arrayHeader(); err != nil || n != 2 {
+			return "", nil, fmt.Errorf("emit: expected [time, record] pair, got %d (%v)", n, err)
+		}
+
+		tm, err := d.int64()
+		if err != nil {
+			return "", nil, err
+		}
+
+		rec, err := d.mapValue()
+		if err != nil {
+			return "", nil, err
+		}
+
+		entries = append(entries, decodedEntry{time: tm, record: rec})
+	}
+	return tag, entries, nil
+}
+
+type decoder struct {
+	b   []byte
+	pos int
+}
+
+func (d *decoder) next() (byte, error) {
+	if d.pos >= len(d.b) {
+		return 0, fmt.Errorf("emit: unexpected end of message at byte %d", d.pos)
+	}
+	v := d.b[d.pos]
+	d.pos++
+	return v, nil
+}
+
+func (d *decoder) arrayHeader() (int, error) {
+	h, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case h&0xf0 == 0x90:
+		return int(h & 0x0f), nil
+	default:
+		return 0, fmt.Errorf("emit: unsupported array header byte 0x%x", h)
+	}
+}
+
+func (d *decoder) mapHeader() (int, error) {
+	h, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case h&0xf0 == 0x80:
+		return int(h & 0x0f), nil
+	default:
+		return 0, fmt.Errorf("emit: unsupported map header byte 0x%x", h)
+	}
+}
+
+func (d *decoder) str() (string, error) {
+	h, err := d.next()
+	if err != nil {
+		return "", err
+	}
+	if h&0xe0 != 0xa0 {
+		return "", fmt.Errorf("emit: unsupported string header byte 0x%x", h)
+	}
+	n := int(h & 0x1f)
+	if d.pos+n > len(d.b) {
+		return "", fmt.Errorf("emit: string runs past end of message")
+	}
+	s := string(d.b[d.pos : d.pos+n])
+	d.pos += n
+	return s, nil
+}
+
+func (d *decoder) int64() (int64, error) {
+	h, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	if h != 0xd3 {
+		return 0, fmt.Errorf("emit: unsupported int header byte 0x%x", h)
+	}
+	if d.pos+8 > len(d.b) {
+		return 0, fmt.Errorf("emit: int64 runs past end of message")
+	}
+	var u uint64
+	for i := 0; i < 8; i++ {
+		u = u<<8 | uint64(d.b[d.pos+i])
+	}
+	d.pos += 8
+	return int64(u), nil
+}
+
+func (d *decoder) value() (interface{}, error) {
+	if d.pos >= len(d.b) {
+		return nil, fmt.Errorf("emit: unexpected end of message at byte %d", d.pos)
+	}
+	h := d.b[d.pos]
+	switch {
+	case h == 0xc0:
+		d.pos++
+		return nil, nil
+	case h == 0xc2:
+		d.pos++
+		return false, nil
+	case h == 0xc3:
+		d.pos++
+		return true, nil
+	case h&0xe0 == 0xa0:
+		return d.str()
+	case h == 0xd3:
+		return d.int64()
+	default:
+		return nil, fmt.Errorf("emit: unsupported value header byte 0x%x", h)
+	}
+}
+
+func (d *decoder) mapValue() (map[string]interface{}, error) {
+	n, err := d.mapHeader()
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
@@ -0,0 +1,38 @@
+package emit
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONEmitter writes each Entry as a single line of newline-delimited JSON (NDJSON) to W.
+type JSONEmitter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONEmitter constructs an Emitter writing NDJSON to w.
+func NewJSONEmitter(w io.Writer) *JSONEmitter {
+	return &JSONEmitter{w: w}
+}
+
+// Emit implements Emitter.
+func (e *JSONEmitter) Emit(entry Entry) error {
+	rec := make(map[string]interface{}, len(entry.Fields)+1)
+	for k, v := range entry.Fields {
+		rec[k] = v
+	}
+	rec["msg"] = entry.Message
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(b)
+	return err
+}
@@ -0,0 +1,53 @@
+package emit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LogfmtEmitter writes each Entry as a single logfmt-encoded (space-separated key=value pairs) line to w.
+type LogfmtEmitter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogfmtEmitter constructs an Emitter writing logfmt lines to w.
+func NewLogfmtEmitter(w io.Writer) *LogfmtEmitter {
+	return &LogfmtEmitter{w: w}
+}
+
+// Emit implements Emitter.
+func (e *LogfmtEmitter) Emit(entry Entry) error {
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("msg=")
+	buf.WriteString(logfmtValue(entry.Message))
+	for _, k := range keys {
+		buf.WriteByte(' ')
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(fmt.Sprintf("%v", entry.Fields[k])))
+	}
+	buf.WriteByte('\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"") {
+		return fmt.Sprintf("%q", v)
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package emit
+
+import (
+	"fmt"
+	"math"
+)
+
+// encodeForwardMessage encodes tag and batch as a Fluentd forward-protocol message:
+//
+//	[tag, [[time, record], [time, record], ...]]
+//
+// in MessagePack. It only implements the subset of MessagePack needed for that shape (strings, maps, arrays,
+// integers, floats, bools and nil); field values of any other type are encoded as their %v string.
+func encodeForwardMessage(tag string, batch []fluentRecord) ([]byte, error) {
+	var buf []byte
+	buf = appendArrayHeader(buf, 2)
+	buf = appendString(buf, tag)
+	buf = appendArrayHeader(buf, len(batch))
+	for _, rec := range batch {
+		buf = appendArrayHeader(buf, 2)
+		buf = appendInt(buf, rec.time)
+
+		var err error
+		buf, err = appendMap(buf, rec.fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func appendValue(buf []byte, v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if x {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case string:
+		return appendString(buf, x), nil
+	case int:
+		return appendInt(buf, int64(x)), nil
+	case int32:
+		return appendInt(buf, int64(x)), nil
+	case int64:
+		return appendInt(buf, x), nil
+	case float32:
+		return appendFloat(buf, float64(x)), nil
+	case float64:
+		return appendFloat(buf, x), nil
+	case map[string]interface{}:
+		return appendMap(buf, x)
+	case []interface{}:
+		buf = appendArrayHeader(buf, len(x))
+		for _, e := range x {
+			var err error
+			buf, err = appendValue(buf, e)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	default:
+		// Best effort: fall back to the value's string form rather than failing the whole batch over one
+		// unsupported field type.
+		return appendString(buf, fmt.Sprintf("%v", x)), nil
+	}
+}
+
+func appendMap(buf []byte, m map[string]interface{}) ([]byte, error) {
+	buf = appendMapHeader(buf, len(m))
+	for k, v := range m {
+		buf = appendString(buf, k)
+		var err error
+		buf, err = appendValue(buf, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n < 1<<8:
+		buf = append(buf, 0xd9, byte(n))
+	case n < 1<<16:
+		buf = append(buf, 0xda, byte(n>>8), byte(n))
+	default:
+		buf = append(buf, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(buf, s...)
+}
+
+func appendInt(buf []byte, n int64) []byte {
+	u := uint64(n)
+	return append(buf, 0xd3,
+		byte(u>>56), byte(u>>48), byte(u>>40), byte(u>>32),
+		byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendFloat(buf []byte, f float64) []byte {
+	u := math.Float64bits(f)
+	return append(buf, 0xcb,
+		byte(u>>56), byte(u>>48), byte(u>>40), byte(u>>32),
+		byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n < 1<<16:
+		return append(buf, 0xdc, byte(n>>8), byte(n))
+	default:
+		return append(buf, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func appendMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n < 1<<16:
+		return append(buf, 0xde, byte(n>>8), byte(n))
+	default:
+		return append(buf, 0xdf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
@@ -0,0 +1,26 @@
+package emit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeForwardMessage_Shape(t *testing.T) {
+	b, err := encodeForwardMessage("app.access", []fluentRecord{
+		{time: 1234, fields: map[string]interface{}{"msg": "ok"}},
+	})
+	require.NoError(t, err)
+
+	// [tag, [[time, record]]]
+	require.Equal(t, byte(0x92), b[0]) // fixarray, 2 elements: tag + entries
+	require.Equal(t, byte(0xaa), b[1]) // fixstr, len("app.access")==10
+
+	tagEnd := 2 + len("app.access")
+	require.Equal(t, "app.access", string(b[2:tagEnd]))
+
+	rest := b[tagEnd:]
+	require.Equal(t, byte(0x91), rest[0]) // fixarray, 1 entry
+	require.Equal(t, byte(0x92), rest[1]) // fixarray, 2 elements: time + record
+	require.Equal(t, byte(0xd3), rest[2]) // int64 time
+}
@@ -0,0 +1,42 @@
+package httplog
+
+import (
+	"fmt"
+
+	"github.com/Bplotka/go-httplog/emit"
+)
+
+// FromEmitter adapts an emit.Emitter into a FieldLogger, so Logger can stream request/response log entries
+// straight to a JSON/logfmt file or Fluentd, without pulling in logrus or any other FieldLogger implementation.
+func FromEmitter(e emit.Emitter) FieldLogger {
+	return emitLogger{emitter: e}
+}
+
+type emitLogger struct {
+	emitter emit.Emitter
+	fields  Fields
+}
+
+// WithFields adds new fields to structured logger.
+func (l emitLogger) WithFields(fields Fields) FieldLogger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return emitLogger{emitter: l.emitter, fields: merged}
+}
+
+// Log hands the accumulated fields and message off to the underlying Emitter.
+func (l emitLogger) Log(args ...interface{}) {
+	fields := make(map[string]interface{}, len(l.fields))
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	_ = l.emitter.Emit(emit.Entry{
+		Message: fmt.Sprint(args...),
+		Fields:  fields,
+	})
+}
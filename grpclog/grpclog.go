@@ -0,0 +1,170 @@
+// Package grpclog provides gRPC server interceptors that log requests/responses the same way httplog's HTTP
+// middleware does, so a service exposing both HTTP and gRPC ends up with uniformly-shaped log entries.
+package grpclog
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bplotka/go-httplog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RequestField is a log field that can be deduced from a gRPC call before it is handled.
+type RequestField string
+
+const (
+	// IDField contains the request ID, taken from the "x-request-id" metadata entry.
+	IDField = RequestField("req_id")
+	// MethodField contains the full gRPC method name, e.g. "/my.pkg.Service/Method".
+	MethodField = RequestField("req_method")
+	// PeerField contains the address of the calling peer.
+	PeerField = RequestField("req_peer")
+	// DeadlineField contains the call's deadline, if any.
+	DeadlineField = RequestField("req_deadline")
+	// UserAgentField contains the "user-agent" metadata entry.
+	UserAgentField = RequestField("req_user_agent")
+)
+
+// DefaultRequestFields is a list for recommended configuration of request fields.
+var DefaultRequestFields = []RequestField{
+	IDField,
+	MethodField,
+	PeerField,
+	DeadlineField,
+	UserAgentField,
+}
+
+// ResponseField is a log field that can be deduced once a gRPC call has completed.
+type ResponseField string
+
+const (
+	// StatusCodeField contains the call's codes.Code, e.g. "OK" or "NotFound".
+	StatusCodeField = ResponseField("res_status_code")
+	// ErrorField contains the call's error message, if it returned one.
+	ErrorField = ResponseField("res_error")
+	// ElapsedField contains how long the call took to complete.
+	ElapsedField = ResponseField("res_elapsed")
+)
+
+// DefaultResponseFields is a list for recommended configuration of response fields.
+var DefaultResponseFields = []ResponseField{
+	StatusCodeField,
+	ErrorField,
+	ElapsedField,
+}
+
+// GRPCConfig is a configuration for grpclog, mirroring httplog.Config.
+type GRPCConfig struct {
+	// RequestFields specifies request fields that should be logged when a call is received (before it is handled).
+	RequestFields []RequestField
+
+	// ResponseFields specifies response fields that should be logged once a call completes.
+	ResponseFields []ResponseField
+	// ResponseReqFields specifies request fields that should be logged once a call completes. It is useful if you
+	// want exactly one combined log line per call -- the usual choice for gRPC, mirroring
+	// httplog.Config.ResponseReqFields.
+	ResponseReqFields []RequestField
+}
+
+// DefaultResponseOnlyConfig is configuration for logging only one entry per call, once it completes, mirroring
+// httplog.DefaultResponseOnlyConfig.
+func DefaultResponseOnlyConfig() GRPCConfig {
+	return GRPCConfig{
+		ResponseReqFields: DefaultRequestFields,
+		ResponseFields:    DefaultResponseFields,
+	}
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	vs := md.Get(key)
+	if len(vs) == 0 {
+		return ""
+	}
+	return vs[0]
+}
+
+func (f RequestField) computeValue(ctx context.Context, fullMethod string) string {
+	switch f {
+	case IDField:
+		return metadataValue(ctx, "x-request-id")
+	case MethodField:
+		return fullMethod
+	case PeerField:
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return ""
+		}
+		return p.Addr.String()
+	case DeadlineField:
+		d, ok := ctx.Deadline()
+		if !ok {
+			return ""
+		}
+		return d.Format(time.RFC3339)
+	case UserAgentField:
+		return metadataValue(ctx, "user-agent")
+	default:
+		return "not supported"
+	}
+}
+
+func (f ResponseField) computeValue(code, errMsg string, elapsed time.Duration) string {
+	switch f {
+	case StatusCodeField:
+		return code
+	case ErrorField:
+		return errMsg
+	case ElapsedField:
+		return elapsed.String()
+	default:
+		return "not supported"
+	}
+}
+
+func logRequest(logger httplog.FieldLogger, fields []RequestField, ctx context.Context, fullMethod string) {
+	f := httplog.Fields{}
+	for _, field := range fields {
+		v := field.computeValue(ctx, fullMethod)
+		if v == "" {
+			continue
+		}
+		f[string(field)] = v
+	}
+
+	l := logger
+	if len(f) != 0 {
+		l = l.WithFields(f)
+	}
+	l.Log("Received gRPC call")
+}
+
+func logResponse(logger httplog.FieldLogger, cfg GRPCConfig, ctx context.Context, fullMethod, code, errMsg string, elapsed time.Duration) {
+	f := httplog.Fields{}
+	for _, field := range cfg.ResponseReqFields {
+		v := field.computeValue(ctx, fullMethod)
+		if v == "" {
+			continue
+		}
+		f[string(field)] = v
+	}
+
+	for _, field := range cfg.ResponseFields {
+		v := field.computeValue(code, errMsg, elapsed)
+		if v == "" {
+			continue
+		}
+		f[string(field)] = v
+	}
+
+	l := logger
+	if len(f) != 0 {
+		l = l.WithFields(f)
+	}
+	l.Log("Finished gRPC call")
+}
@@ -0,0 +1,41 @@
+package grpclog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestField_ComputeValue(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "req-1",
+		"user-agent", "test-client/1.0",
+	))
+
+	require.Equal(t, "req-1", IDField.computeValue(ctx, "/my.pkg.Service/Method"))
+	require.Equal(t, "/my.pkg.Service/Method", MethodField.computeValue(ctx, "/my.pkg.Service/Method"))
+	require.Equal(t, "test-client/1.0", UserAgentField.computeValue(ctx, "/my.pkg.Service/Method"))
+	require.Equal(t, "", PeerField.computeValue(ctx, "/my.pkg.Service/Method"))
+	require.Equal(t, "", DeadlineField.computeValue(ctx, "/my.pkg.Service/Method"))
+}
+
+func TestRequestField_ComputeValue_NoMetadata(t *testing.T) {
+	require.Equal(t, "", IDField.computeValue(context.Background(), "/my.pkg.Service/Method"))
+}
+
+func TestRequestField_ComputeValue_Deadline(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	require.Equal(t, deadline.Format(time.RFC3339), DeadlineField.computeValue(ctx, "/my.pkg.Service/Method"))
+}
+
+func TestResponseField_ComputeValue(t *testing.T) {
+	require.Equal(t, "NotFound", StatusCodeField.computeValue("NotFound", "not found", time.Second))
+	require.Equal(t, "not found", ErrorField.computeValue("NotFound", "not found", time.Second))
+	require.Equal(t, "1s", ElapsedField.computeValue("OK", "", time.Second))
+}
@@ -0,0 +1,60 @@
+package grpclog
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bplotka/go-httplog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs each unary call the same way httplog.RegisterMiddleware logs an HTTP request:
+// RequestFields are logged when the call is received, ResponseFields/ResponseReqFields once it completes.
+func UnaryServerInterceptor(logger httplog.FieldLogger, cfg GRPCConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		if len(cfg.RequestFields) != 0 {
+			logRequest(logger, cfg.RequestFields, ctx, info.FullMethod)
+		}
+
+		resp, err := handler(ctx, req)
+
+		if len(cfg.ResponseFields) != 0 || len(cfg.ResponseReqFields) != 0 {
+			logResponse(logger, cfg, ctx, info.FullMethod, status.Code(err).String(), errMessage(err), time.Since(start))
+		}
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+func StreamServerInterceptor(logger httplog.FieldLogger, cfg GRPCConfig) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := ss.Context()
+
+		if len(cfg.RequestFields) != 0 {
+			logRequest(logger, cfg.RequestFields, ctx, info.FullMethod)
+		}
+
+		err := handler(srv, ss)
+
+		if len(cfg.ResponseFields) != 0 || len(cfg.ResponseReqFields) != 0 {
+			logResponse(logger, cfg, ctx, info.FullMethod, status.Code(err).String(), errMessage(err), time.Since(start))
+		}
+
+		return err
+	}
+}
+
+func errMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Message()
+	}
+	return err.Error()
+}
@@ -0,0 +1,115 @@
+package grpclog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Bplotka/go-httplog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// recordingLogger is a minimal httplog.FieldLogger double recording every WithFields call and the final Log args,
+// mirroring the one echo/middleware_test.go uses for the HTTP side.
+type recordingLogger struct {
+	fields []httplog.Fields
+	logs   [][]interface{}
+}
+
+func (l *recordingLogger) WithFields(fields httplog.Fields) httplog.FieldLogger {
+	l.fields = append(l.fields, fields)
+	return l
+}
+
+func (l *recordingLogger) Log(args ...interface{}) {
+	l.logs = append(l.logs, args)
+}
+
+func TestUnaryServerInterceptor_LogsReceivedAndFinished(t *testing.T) {
+	logger := &recordingLogger{}
+	interceptor := UnaryServerInterceptor(logger, GRPCConfig{
+		RequestFields:  DefaultRequestFields,
+		ResponseFields: DefaultResponseFields,
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/my.pkg.Service/Method"}
+
+	resp, err := interceptor(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Len(t, logger.logs, 2)
+	require.Equal(t, []interface{}{"Received gRPC call"}, logger.logs[0])
+	require.Equal(t, []interface{}{"Finished gRPC call"}, logger.logs[1])
+
+	require.Equal(t, "req-1", logger.fields[0]["req_id"])
+	require.Equal(t, "/my.pkg.Service/Method", logger.fields[0]["req_method"])
+	require.Equal(t, "OK", logger.fields[1]["res_status_code"])
+}
+
+func TestUnaryServerInterceptor_LogsErrorStatus(t *testing.T) {
+	logger := &recordingLogger{}
+	interceptor := UnaryServerInterceptor(logger, GRPCConfig{ResponseFields: DefaultResponseFields})
+
+	_, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: "/my.pkg.Service/Method"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		},
+	)
+
+	require.Error(t, err)
+	require.Len(t, logger.logs, 1)
+	require.Equal(t, []interface{}{"Finished gRPC call"}, logger.logs[0])
+	require.Equal(t, "NotFound", logger.fields[0]["res_status_code"])
+	require.Equal(t, "not found", logger.fields[0]["res_error"])
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor_LogsReceivedAndFinished(t *testing.T) {
+	logger := &recordingLogger{}
+	interceptor := StreamServerInterceptor(logger, GRPCConfig{
+		RequestFields:  DefaultRequestFields,
+		ResponseFields: DefaultResponseFields,
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-2"))
+	stream := &fakeServerStream{ctx: ctx}
+	info := &grpc.StreamServerInfo{FullMethod: "/my.pkg.Service/Stream"}
+
+	err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, logger.logs, 2)
+	require.Equal(t, "req-2", logger.fields[0]["req_id"])
+	require.Equal(t, "OK", logger.fields[1]["res_status_code"])
+}
+
+func TestStreamServerInterceptor_LogsHandlerError(t *testing.T) {
+	logger := &recordingLogger{}
+	interceptor := StreamServerInterceptor(logger, GRPCConfig{ResponseFields: DefaultResponseFields})
+
+	stream := &fakeServerStream{ctx: context.Background()}
+	info := &grpc.StreamServerInfo{FullMethod: "/my.pkg.Service/Stream"}
+
+	err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return errors.New("boom")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, "boom", logger.fields[0]["res_error"])
+}
@@ -31,6 +31,22 @@ type Config struct {
 	// ResponseReqFields specifies request fields that should be logged when response is returned/redirected
 	// (right after server handling). It is useful if you want to log only once per request. (common logging technique)
 	ResponseReqFields []RequestField
+
+	// Sampler decides, once the response status is known, whether this request's entries should be logged at all.
+	// Defaults to AlwaysSample (log everything) if left nil and SampleRate/SampleEveryN are also unset.
+	Sampler Sampler
+	// SampleRate, if > 0 and Sampler is nil, builds a RatioSampler(SampleRate) and uses it as Sampler.
+	SampleRate float64
+	// SampleEveryN, if > 0 and both Sampler and SampleRate are unset, logs exactly 1 out of every N entries.
+	SampleEveryN int
+	// LogOnly, if set, restricts logging to statuses it matches regardless of what the sampler decides -- e.g.
+	// LogOnlyClasses(4, 5) to always drop 2xx/3xx noise while still honoring sampling for the errors that remain.
+	LogOnly StatusFilter
+
+	// DeferRequestLogToSampler, if true, makes RequestHandler buffer its "Received HTTP request" entry instead of
+	// logging it immediately, emitting it only once responseLogger.log has decided (via Sampler/LogOnly) that the
+	// response itself is worth logging.
+	DeferRequestLogToSampler bool
 }
 
 // Logger is an instance for httplog to register middleware and wrap response.
@@ -39,25 +55,49 @@ type Logger struct {
 	// TODO(bplotka): Add default FieldLogger (using Bplotka/sgl e.g)
 	logger FieldLogger
 	cfg    Config
+
+	// sampler is built once from cfg in New, not per-request -- see Config.sampler.
+	sampler Sampler
 }
 
 // New constructs new httplog Logger.
 func New(logger FieldLogger, cfg Config) *Logger {
 	timeNow = time.Now
 	return &Logger{
-		logger: logger,
-		cfg:    cfg,
+		logger:  logger,
+		cfg:     cfg,
+		sampler: cfg.sampler(),
 	}
 }
 
+// PrepareRequest installs the request-scoped FieldLogger, response-fields accumulator, request-body byte counter
+// and deferred-request-log buffer that RegisterMiddleware wires into every request. Framework adapters other than
+// net/http (see echo/middleware.go) call this instead of duplicating the wiring.
+func (l *Logger) PrepareRequest(r *http.Request) *http.Request {
+	bodyCounter := &requestBodyCounter{ReadCloser: r.Body}
+	r.Body = bodyCounter
+
+	ctx := NewContext(r.Context(), l.requestLogger(r))
+	ctx = newResponseFieldsContext(ctx)
+	ctx = withRequestBodyCounter(ctx, bodyCounter)
+	ctx = newDeferredRequestLogContext(ctx)
+	return r.WithContext(ctx)
+}
+
 // RegisterMiddleware registers handler that will log request at the beginning and served response at the request end.
+// It also installs a request-scoped FieldLogger (already decorated with RequestFields) into the request context, so
+// downstream handlers can fetch it via FromContext and add extra fields to the same log line via AddResponseFields.
 func RegisterMiddleware(logger FieldLogger, cfg Config) func(http.Handler) http.Handler {
 	l := New(logger, cfg)
 
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Log specified RequestFields now.
+			r = l.PrepareRequest(r)
+
+			// Log specified RequestFields now, unless DeferRequestLogToSampler buffers it for responseLogger.log
+			// to flush once the sampling decision for the response is known.
 			l.RequestHandler()(w, r)
+
 			h.ServeHTTP(
 				// Log specified ResponseFields and ResponseReqFields on Response Write or Redirect.
 				l.WrapResponse(w, r),
@@ -74,33 +114,31 @@ func (l *Logger) RequestHandler() func(w http.ResponseWriter, r *http.Request) {
 	}
 
 	return func(_ http.ResponseWriter, r *http.Request) {
-		f := Fields{}
-		for _, field := range l.cfg.RequestFields {
-			v := field.computeValue(timeNow, r)
-			if v == "" {
-				continue
-			}
-			f[string(field)] = v
+		if l.cfg.DeferRequestLogToSampler {
+			markDeferredRequestLog(r.Context(), "Received HTTP request")
+			return
 		}
+		l.requestLogger(r).Log("Received HTTP request")
+	}
+}
 
-		logger := l.logger
-		if len(f) != 0 {
-			logger = logger.WithFields(f)
+// requestLogger builds the FieldLogger decorated with l.cfg.RequestFields computed from r. It is shared by
+// RequestHandler (for the "Received" log line) and PrepareRequest (for the logger installed into the request
+// context via NewContext).
+func (l *Logger) requestLogger(r *http.Request) FieldLogger {
+	f := Fields{}
+	for _, field := range l.cfg.RequestFields {
+		v := field.computeValue(timeNow, r)
+		if v == "" {
+			continue
 		}
-		logger.Log("Received HTTP request")
+		f[string(field)] = v
 	}
-}
 
-// WrapResponse wraps ResponseWriter. It is the only way to get details about response without including custom code
-// in user handlers.
-func (l *Logger) WrapResponse(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
-	return &responseLogger{
-		writer:  w,
-		req:     r,
-		cfg:     l.cfg,
-		logger:  l.logger,
-		timeNow: timeNow,
+	if len(f) == 0 {
+		return l.logger
 	}
+	return l.logger.WithFields(f)
 }
 
 // RequestField is a log field that can be deducted from http.Request.
@@ -123,7 +161,11 @@ const (
 	MethodField = RequestField("req_method")
 	// PathField contains path of request.
 	PathField = RequestField("req_path")
-	// BytesInField contains size of request in bytes.
+	// BytesInField contains the number of request body bytes actually read off the wire, via the same counter
+	// RegisterMiddleware/PrepareRequest install. Since a handler typically hasn't read the body yet by the time
+	// RequestFields are computed, this field only reports a real count when used as a ResponseReqField (computed
+	// after the handler ran); falls back to the Content-Length header when no counter is installed in the request
+	// context, e.g. when computeValue is invoked directly without going through RegisterMiddleware/PrepareRequest.
 	BytesInField = RequestField("req_bytes_in")
 	// AuthField contains auth header for request.
 	AuthField = RequestField("req_auth_header")
@@ -149,7 +191,8 @@ type ResponseField string
 const (
 	// StatusField contains status code.
 	StatusField = ResponseField("res_status")
-	// BytesOutField contains size of response in bytes.
+	// BytesOutField contains size of response in bytes, including an approximation of the status line and header
+	// bytes written (see ResHeaderBytesField) on top of the response body.
 	BytesOutField = ResponseField("res_bytes_out")
 	// ResTimeField contains time returning response or redirecting.
 	ResTimeField = ResponseField("res_time")
@@ -161,6 +204,10 @@ const (
 	LocationCompactArgsField = ResponseField("res_location_compact")
 	// LocationHostField contains host of redirection URL in case of redirection response.
 	LocationHostField = ResponseField("res_location_host")
+	// ResHeaderBytesField contains an approximation of the bytes taken by the response status line and headers.
+	ResHeaderBytesField = ResponseField("res_header_bytes")
+	// TotalBytesField contains the sum of request body bytes read, response header bytes and response body bytes.
+	TotalBytesField = ResponseField("total_bytes")
 )
 
 // DefaultResponseFields is a list for recommended configuration of response fields.
@@ -251,6 +298,9 @@ func (f RequestField) computeValue(timeNow func() time.Time, req *http.Request)
 		}
 		return p
 	case BytesInField:
+		if c := requestBodyCounterFromContext(req.Context()); c != nil {
+			return fmt.Sprintf("%d", c.Bytes())
+		}
 		cl := req.Header.Get("Content-Length")
 		if cl == "" {
 			cl = "0"
@@ -268,7 +318,7 @@ func (f ResponseField) computeValue(timeNow func() time.Time, res *responseLogge
 	case StatusField:
 		return fmt.Sprintf("%d", res.status)
 	case BytesOutField:
-		return fmt.Sprintf("%d", res.size)
+		return fmt.Sprintf("%d", res.headerBytes+res.size)
 	case ResTimeField:
 		return timeNow().Format(time.RFC3339)
 	case ContentTypeField:
@@ -287,6 +337,14 @@ func (f ResponseField) computeValue(timeNow func() time.Time, res *responseLogge
 			return ""
 		}
 		return splittedQuery[0]
+	case ResHeaderBytesField:
+		return fmt.Sprintf("%d", res.headerBytes)
+	case TotalBytesField:
+		var reqBody int64
+		if c := requestBodyCounterFromContext(res.req.Context()); c != nil {
+			reqBody = c.Bytes()
+		}
+		return fmt.Sprintf("%d", reqBody+res.headerBytes+res.size)
 	default:
 		return "not supported"
 	}
@@ -294,14 +352,16 @@ func (f ResponseField) computeValue(timeNow func() time.Time, res *responseLogge
 
 // responseLogger is light wrapper of ResponseWriter and Flusher to support logging on response.
 type responseLogger struct {
-	writer    http.ResponseWriter
-	req       *http.Request
-	cfg       Config
-	logger    FieldLogger
-	status    int
-	size      int64
-	committed bool
-	logged    bool
+	writer      http.ResponseWriter
+	req         *http.Request
+	cfg         Config
+	logger      FieldLogger
+	sampler     Sampler
+	status      int
+	size        int64
+	headerBytes int64
+	committed   bool
+	logged      bool
 
 	timeNow func() time.Time
 }
@@ -312,6 +372,12 @@ func (r *responseLogger) Header() http.Header {
 	return r.writer.Header()
 }
 
+// Committed reports whether the status line and headers have already been written, e.g. so RecoveryMiddleware can
+// tell whether it is still safe to write its own 500 response.
+func (r *responseLogger) Committed() bool {
+	return r.committed
+}
+
 // WriteHeader wraps writer WriteHeader method.
 // See [http.ResponseWriter](https://golang.org/pkg/net/http/#ResponseWriter)
 func (r *responseLogger) WriteHeader(code int) {
@@ -319,6 +385,7 @@ func (r *responseLogger) WriteHeader(code int) {
 		return
 	}
 	r.status = code
+	r.headerBytes = approxHeaderBytes(code, r.Header())
 	r.writer.WriteHeader(code)
 	r.committed = true
 
@@ -356,11 +423,28 @@ func (r *responseLogger) parseJSON(b []byte) FieldLogger {
 	return r.logger
 }
 
+// shouldLog reports whether an entry for r.status should be emitted at all, combining r.cfg.LogOnly with r.sampler.
+func (r *responseLogger) shouldLog() bool {
+	if r.cfg.LogOnly != nil && !r.cfg.LogOnly(r.status) {
+		return false
+	}
+	return r.sampler.Sample(r.status)
+}
+
 func (r *responseLogger) log(b []byte) {
 	if r.logged {
 		return
 	}
 	r.logged = true
+
+	if !r.shouldLog() {
+		return
+	}
+
+	// Flush the "Received HTTP request" entry RequestHandler buffered (Config.DeferRequestLogToSampler), now that
+	// we know this request is actually worth logging.
+	flushDeferredRequestLog(r.req.Context(), FromContext(r.req.Context()))
+
 	logger := r.parseBody(b)
 
 	f := Fields{}
@@ -380,6 +464,11 @@ func (r *responseLogger) log(b []byte) {
 		f[string(field)] = v
 	}
 
+	// Merge in any extra fields application code attached via AddResponseFields while handling the request.
+	for k, v := range responseFieldsFromContext(r.req.Context()) {
+		f[k] = v
+	}
+
 	if len(f) != 0 {
 		logger = logger.WithFields(f)
 	}
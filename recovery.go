@@ -0,0 +1,56 @@
+package httplog
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+const (
+	// PanicField contains the recovered panic value, set by RecoveryMiddleware on the entry covering a request
+	// whose handler panicked.
+	PanicField = ResponseField("panic")
+	// PanicStackField contains the runtime/debug.Stack() trace captured at the point of the panic.
+	PanicStackField = ResponseField("panic_stack")
+)
+
+// committer is implemented by responseLogger (and anything WrapResponse returns, since it always embeds
+// *responseLogger) so RecoveryMiddleware can tell whether a response was already (partially) written.
+type committer interface {
+	Committed() bool
+}
+
+// RecoveryMiddleware recovers from panics raised by the wrapped handler, attaching the panic value and stack trace
+// to the request's log entry via AddResponseFields. Must run inside the handler chain RegisterMiddleware wraps:
+//
+//	mux = httplog.RegisterMiddleware(logger, cfg)(httplog.RecoveryMiddleware(logger)(mux))
+func RecoveryMiddleware(logger FieldLogger) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+
+				fields := Fields{
+					string(PanicField):      fmt.Sprintf("%v", rec),
+					string(PanicStackField): string(debug.Stack()),
+				}
+				AddResponseFields(r.Context(), fields)
+
+				if c, ok := w.(committer); ok && c.Committed() {
+					// Response already (partially) sent: its entry was either logged on the first Write or, after a
+					// bare WriteHeader, is never logged at all. Emit a standalone correlated line instead.
+					logger.WithFields(fields).Log("recovered from panic after response already committed")
+					return
+				}
+
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("Internal Server Error\n"))
+			}()
+			h.ServeHTTP(w, r)
+		})
+	}
+}
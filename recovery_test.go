@@ -0,0 +1,54 @@
+package httplog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryMiddleware_RecoversAndWrites500(t *testing.T) {
+	mLogger := new(MockFieldLogger)
+	mLogger.On("WithFields", mock.Anything).Return(mLogger)
+	mLogger.On("Log", mock.Anything)
+	handler := RegisterMiddleware(mLogger, DefaultReqResConfig())(
+		RecoveryMiddleware(mLogger)(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			}),
+		),
+	)
+
+	req := httptest.NewRequest("GET", "/some_endpoint", nil)
+	rec := httptest.NewRecorder()
+
+	require.NotPanics(t, func() {
+		handler.ServeHTTP(rec, req)
+	})
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestRecoveryMiddleware_NoPanic_PassesThrough(t *testing.T) {
+	mLogger := new(MockFieldLogger)
+	mLogger.On("WithFields", mock.Anything).Return(mLogger)
+	mLogger.On("Log", mock.Anything)
+	handler := RegisterMiddleware(mLogger, DefaultReqResConfig())(
+		RecoveryMiddleware(mLogger)(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("ok"))
+			}),
+		),
+	)
+
+	req := httptest.NewRequest("GET", "/some_endpoint", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ok", rec.Body.String())
+}
@@ -0,0 +1,137 @@
+package httplog
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides, once a response status is known, whether the entry/entries covering that request should
+// actually be logged. It lets high-traffic services drop routine 2xx noise while still keeping every error.
+type Sampler interface {
+	Sample(status int) bool
+}
+
+// AlwaysSample logs every entry. It is the Sampler responseLogger.log uses when Config.Sampler, SampleRate and
+// SampleEveryN are all left unset.
+type AlwaysSample struct{}
+
+// Sample implements Sampler.
+func (AlwaysSample) Sample(status int) bool { return true }
+
+// ErrorsOnlySampler only samples responses with a 4xx or 5xx status, dropping everything else.
+type ErrorsOnlySampler struct{}
+
+// Sample implements Sampler.
+func (ErrorsOnlySampler) Sample(status int) bool { return status >= 400 }
+
+// randShard is a mutex-guarded *rand.Rand. RatioSampler keeps one per CPU so concurrent requests hitting Sample
+// rarely contend on the same shard's mutex, unlike a single package-level rand.Rand would.
+type randShard struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (s *randShard) float64() float64 {
+	s.mu.Lock()
+	v := s.r.Float64()
+	s.mu.Unlock()
+	return v
+}
+
+// RatioSampler samples a fixed fraction of entries, independent of their response status.
+type RatioSampler struct {
+	rate   float64
+	shards []*randShard
+	next   uint32
+}
+
+// NewRatioSampler constructs a RatioSampler that samples roughly rate (0..1) of entries.
+func NewRatioSampler(rate float64) *RatioSampler {
+	n := runtime.NumCPU()
+	shards := make([]*randShard, n)
+	for i := range shards {
+		shards[i] = &randShard{r: rand.New(rand.NewSource(time.Now().UnixNano() + int64(i)))}
+	}
+	return &RatioSampler{rate: rate, shards: shards}
+}
+
+// Sample implements Sampler.
+func (s *RatioSampler) Sample(status int) bool {
+	if s.rate >= 1 {
+		return true
+	}
+	if s.rate <= 0 {
+		return false
+	}
+
+	// Picking a shard via a single atomic counter is still one shared cache line, but it's far cheaper than the
+	// mutex+rand call it replaces would be if shared across all goroutines, which is what actually dominated
+	// contention under load.
+	idx := atomic.AddUint32(&s.next, 1)
+	shard := s.shards[int(idx)%len(s.shards)]
+	return shard.float64() < s.rate
+}
+
+// everyNSampler logs exactly the Nth entry out of every N seen, regardless of status.
+type everyNSampler struct {
+	n       int64
+	counter int64
+}
+
+// NewEveryNSampler constructs a Sampler that logs 1 out of every n entries.
+func NewEveryNSampler(n int) Sampler {
+	if n <= 1 {
+		return AlwaysSample{}
+	}
+	return &everyNSampler{n: int64(n)}
+}
+
+// Sample implements Sampler.
+func (s *everyNSampler) Sample(status int) bool {
+	return atomic.AddInt64(&s.counter, 1)%s.n == 0
+}
+
+// StatusFilter reports whether status is loggable at all, independent of sampling. Config.LogOnly layers this on
+// top of Config.Sampler so e.g. "only keep 4xx/5xx" can be expressed without writing a custom Sampler.
+type StatusFilter func(status int) bool
+
+// LogOnlyClasses builds a StatusFilter matching whole status classes, e.g. LogOnlyClasses(4, 5) keeps 4xx and 5xx.
+func LogOnlyClasses(classes ...int) StatusFilter {
+	set := make(map[int]bool, len(classes))
+	for _, c := range classes {
+		set[c] = true
+	}
+	return func(status int) bool {
+		return set[status/100]
+	}
+}
+
+// LogOnlyCodes builds a StatusFilter matching an explicit set of status codes.
+func LogOnlyCodes(codes ...int) StatusFilter {
+	set := make(map[int]bool, len(codes))
+	for _, c := range codes {
+		set[c] = true
+	}
+	return func(status int) bool {
+		return set[status]
+	}
+}
+
+// sampler returns cfg.Sampler, building one from SampleRate/SampleEveryN if it was left nil, defaulting to
+// AlwaysSample if none of those are set either. Logger.New calls this once and caches the result -- building fresh
+// per request would reset everyNSampler's counter back to zero every time.
+func (cfg Config) sampler() Sampler {
+	if cfg.Sampler != nil {
+		return cfg.Sampler
+	}
+	if cfg.SampleRate > 0 {
+		return NewRatioSampler(cfg.SampleRate)
+	}
+	if cfg.SampleEveryN > 0 {
+		return NewEveryNSampler(cfg.SampleEveryN)
+	}
+	return AlwaysSample{}
+}
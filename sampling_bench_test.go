@@ -0,0 +1,95 @@
+package httplog
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// BenchmarkResponseLogger_Log_SampledOut proves that dropping an entry via a Sampler stays cheap: once it says no,
+// log() returns right after, without ever touching RequestFields/ResponseFields or the logger. The Sampler itself
+// is built exactly once, outside the loop -- mirroring what Logger.New does in production -- since rebuilding one
+// per request (e.g. RatioSampler's runtime.NumCPU() rand shards) would be the actual cost worth measuring.
+func BenchmarkResponseLogger_Log_SampledOut(b *testing.B) {
+	timeNow = time.Now
+	cfg := Config{
+		RequestFields:     DefaultRequestFields,
+		ResponseFields:    DefaultResponseFields,
+		ResponseReqFields: DefaultRequestFields,
+	}
+	sampler := ErrorsOnlySampler{}
+
+	for i := 0; i < b.N; i++ {
+		rl := &responseLogger{
+			writer:  discardResponseWriter{},
+			req:     benchRequest(),
+			cfg:     cfg,
+			logger:  discardLogger{},
+			sampler: sampler,
+			timeNow: timeNow,
+			status:  200,
+		}
+		rl.log(nil)
+	}
+}
+
+// BenchmarkResponseLogger_Log_SampledOutByRatio is BenchmarkResponseLogger_Log_SampledOut with a RatioSampler
+// instead, the case that actually allocates rand shards -- again built once, as Logger.New does.
+func BenchmarkResponseLogger_Log_SampledOutByRatio(b *testing.B) {
+	timeNow = time.Now
+	cfg := Config{
+		RequestFields:     DefaultRequestFields,
+		ResponseFields:    DefaultResponseFields,
+		ResponseReqFields: DefaultRequestFields,
+	}
+	sampler := NewRatioSampler(0)
+
+	for i := 0; i < b.N; i++ {
+		rl := &responseLogger{
+			writer:  discardResponseWriter{},
+			req:     benchRequest(),
+			cfg:     cfg,
+			logger:  discardLogger{},
+			sampler: sampler,
+			timeNow: timeNow,
+			status:  200,
+		}
+		rl.log(nil)
+	}
+}
+
+// BenchmarkResponseLogger_Log_AlwaysSampled is the baseline the above should stay close to.
+func BenchmarkResponseLogger_Log_AlwaysSampled(b *testing.B) {
+	timeNow = time.Now
+	cfg := Config{
+		RequestFields:     DefaultRequestFields,
+		ResponseFields:    DefaultResponseFields,
+		ResponseReqFields: DefaultRequestFields,
+	}
+	sampler := AlwaysSample{}
+
+	for i := 0; i < b.N; i++ {
+		rl := &responseLogger{
+			writer:  discardResponseWriter{},
+			req:     benchRequest(),
+			cfg:     cfg,
+			logger:  discardLogger{},
+			sampler: sampler,
+			timeNow: timeNow,
+			status:  200,
+		}
+		rl.log(nil)
+	}
+}
+
+func benchRequest() *http.Request {
+	r, _ := http.NewRequest("GET", "/some_endpoint?arg1=v", nil)
+	r = r.WithContext(newResponseFieldsContext(newDeferredRequestLogContext(r.Context())))
+	return r
+}
+
+type discardResponseWriter struct{}
+
+func (discardResponseWriter) Header() http.Header        { return http.Header{} }
+func (discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (discardResponseWriter) WriteHeader(int)             {}
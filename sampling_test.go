@@ -0,0 +1,79 @@
+package httplog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorsOnlySampler(t *testing.T) {
+	s := ErrorsOnlySampler{}
+	require.False(t, s.Sample(200))
+	require.False(t, s.Sample(304))
+	require.True(t, s.Sample(404))
+	require.True(t, s.Sample(500))
+}
+
+func TestRatioSampler_Extremes(t *testing.T) {
+	require.True(t, NewRatioSampler(1).Sample(200))
+	require.False(t, NewRatioSampler(0).Sample(200))
+}
+
+func TestEveryNSampler_LogsOneOutOfN(t *testing.T) {
+	s := NewEveryNSampler(3)
+
+	require.False(t, s.Sample(200))
+	require.False(t, s.Sample(200))
+	require.True(t, s.Sample(200))
+	require.False(t, s.Sample(200))
+}
+
+func TestLogOnlyClasses(t *testing.T) {
+	f := LogOnlyClasses(4, 5)
+
+	require.False(t, f(200))
+	require.False(t, f(301))
+	require.True(t, f(404))
+	require.True(t, f(503))
+}
+
+func TestLogOnlyCodes(t *testing.T) {
+	f := LogOnlyCodes(418, 429)
+
+	require.False(t, f(200))
+	require.True(t, f(418))
+	require.True(t, f(429))
+}
+
+func TestResponseLogger_ShouldLog_CombinesSamplerAndLogOnly(t *testing.T) {
+	rl := &responseLogger{
+		cfg:     Config{LogOnly: LogOnlyClasses(5)},
+		sampler: AlwaysSample{},
+	}
+
+	rl.status = 200
+	require.False(t, rl.shouldLog())
+	rl.status = 500
+	require.True(t, rl.shouldLog())
+}
+
+func TestLogger_New_BuildsSamplerOnceFromSampleRate(t *testing.T) {
+	cfg := Config{SampleRate: 1}
+	l := New(new(MockFieldLogger), cfg)
+	require.NotNil(t, l.sampler)
+	require.True(t, l.sampler.Sample(200))
+}
+
+func TestLogger_New_CachesSampleEveryNAcrossRequests(t *testing.T) {
+	// Regression test: Logger.New must build the Sampler exactly once and reuse it for every request. Building a
+	// fresh everyNSampler per request (e.g. from a copied Config) would reset its counter back to zero every
+	// time, so SampleEveryN would never fire for any N >= 2.
+	l := New(new(MockFieldLogger), Config{SampleEveryN: 3})
+
+	var sampled []bool
+	for i := 0; i < 6; i++ {
+		sampled = append(sampled, l.sampler.Sample(200))
+	}
+
+	require.Equal(t, []bool{false, false, true, false, false, true}, sampled)
+}
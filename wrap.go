@@ -0,0 +1,382 @@
+package httplog
+
+import (
+	"io"
+	"net/http"
+)
+
+// WrapResponse wraps ResponseWriter. It is the only way to get details about response without including custom code
+// in user handlers.
+//
+// Returning plain *responseLogger would drop http.Flusher/http.Hijacker/http.CloseNotifier/http.Pusher/io.ReaderFrom
+// from w's method set even when w implements them, breaking downstream type assertions (e.g. WebSocket upgrades,
+// SSE). So, mirroring httpsnoop, we return a wrapper embedding exactly the optional interfaces w supports.
+func (l *Logger) WrapResponse(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	rl := &responseLogger{
+		writer:  w,
+		req:     r,
+		cfg:     l.cfg,
+		logger:  l.logger,
+		sampler: l.sampler,
+		timeNow: timeNow,
+	}
+
+	_, okFlusher := w.(http.Flusher)
+	_, okHijacker := w.(http.Hijacker)
+	_, okCloseNotifier := w.(http.CloseNotifier)
+	_, okPusher := w.(http.Pusher)
+	_, okReaderFrom := w.(io.ReaderFrom)
+
+	// One anonymous struct per combination of the five optional interfaces, embedding *responseLogger plus
+	// whichever of them w supports.
+	switch {
+	case okFlusher && okHijacker && okCloseNotifier && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			http.CloseNotifier
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okHijacker && okCloseNotifier && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			http.CloseNotifier
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okCloseNotifier && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.CloseNotifier
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okCloseNotifier && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.CloseNotifier
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okHijacker && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okHijacker && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okPusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Pusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Pusher),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okHijacker && okCloseNotifier && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			http.CloseNotifier
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+			w.(io.ReaderFrom),
+		}
+	case okHijacker && okCloseNotifier && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			http.CloseNotifier
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okCloseNotifier && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.CloseNotifier
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.CloseNotifier),
+			w.(io.ReaderFrom),
+		}
+	case okCloseNotifier && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.CloseNotifier
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.CloseNotifier),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okHijacker && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(io.ReaderFrom),
+		}
+	case okHijacker && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okReaderFrom:
+		return struct {
+			*responseLogger
+			http.Flusher
+			io.ReaderFrom
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(io.ReaderFrom),
+		}
+	case okReaderFrom:
+		return struct {
+			*responseLogger
+			io.ReaderFrom
+		}{
+			rl,
+			w.(io.ReaderFrom),
+		}
+	case okFlusher && okHijacker && okCloseNotifier && okPusher:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			http.CloseNotifier
+			http.Pusher
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+		}
+	case okHijacker && okCloseNotifier && okPusher:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			http.CloseNotifier
+			http.Pusher
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+		}
+	case okFlusher && okCloseNotifier && okPusher:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.CloseNotifier
+			http.Pusher
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+		}
+	case okCloseNotifier && okPusher:
+		return struct {
+			*responseLogger
+			http.CloseNotifier
+			http.Pusher
+		}{
+			rl,
+			w.(http.CloseNotifier),
+			w.(http.Pusher),
+		}
+	case okFlusher && okHijacker && okPusher:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(http.Pusher),
+		}
+	case okHijacker && okPusher:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			http.Pusher
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(http.Pusher),
+		}
+	case okFlusher && okPusher:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Pusher
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Pusher),
+		}
+	case okPusher:
+		return struct {
+			*responseLogger
+			http.Pusher
+		}{
+			rl,
+			w.(http.Pusher),
+		}
+	case okFlusher && okHijacker && okCloseNotifier:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+			http.CloseNotifier
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+		}
+	case okHijacker && okCloseNotifier:
+		return struct {
+			*responseLogger
+			http.Hijacker
+			http.CloseNotifier
+		}{
+			rl,
+			w.(http.Hijacker),
+			w.(http.CloseNotifier),
+		}
+	case okFlusher && okCloseNotifier:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.CloseNotifier
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.CloseNotifier),
+		}
+	case okCloseNotifier:
+		return struct {
+			*responseLogger
+			http.CloseNotifier
+		}{
+			rl,
+			w.(http.CloseNotifier),
+		}
+	case okFlusher && okHijacker:
+		return struct {
+			*responseLogger
+			http.Flusher
+			http.Hijacker
+		}{
+			rl,
+			w.(http.Flusher),
+			w.(http.Hijacker),
+		}
+	case okHijacker:
+		return struct {
+			*responseLogger
+			http.Hijacker
+		}{
+			rl,
+			w.(http.Hijacker),
+		}
+	case okFlusher:
+		return struct {
+			*responseLogger
+			http.Flusher
+		}{
+			rl,
+			w.(http.Flusher),
+		}
+	default:
+		return rl
+	}
+}
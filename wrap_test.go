@@ -0,0 +1,79 @@
+package httplog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// plainResponseWriter only implements http.ResponseWriter, nothing else.
+type plainResponseWriter struct {
+	http.ResponseWriter
+}
+
+// fullResponseWriter implements every optional interface WrapResponse knows about.
+type fullResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (fullResponseWriter) Flush()                                           {}
+func (fullResponseWriter) CloseNotify() <-chan bool                         { return nil }
+func (fullResponseWriter) Push(target string, opts *http.PushOptions) error { return nil }
+func (fullResponseWriter) ReadFrom(src io.Reader) (int64, error)            { return 0, nil }
+func (fullResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error)     { return nil, nil, nil }
+
+func TestLogger_WrapResponse_PreservesNoOptionalInterfaces(t *testing.T) {
+	l := New(new(MockFieldLogger), Config{})
+	req := httptest.NewRequest("GET", "/", nil)
+
+	w := l.WrapResponse(plainResponseWriter{httptest.NewRecorder()}, req)
+
+	_, ok := w.(http.Flusher)
+	require.False(t, ok)
+	_, ok = w.(http.Hijacker)
+	require.False(t, ok)
+	_, ok = w.(http.CloseNotifier)
+	require.False(t, ok)
+	_, ok = w.(http.Pusher)
+	require.False(t, ok)
+	_, ok = w.(io.ReaderFrom)
+	require.False(t, ok)
+}
+
+func TestLogger_WrapResponse_PreservesAllOptionalInterfaces(t *testing.T) {
+	l := New(new(MockFieldLogger), Config{})
+	req := httptest.NewRequest("GET", "/", nil)
+
+	w := l.WrapResponse(fullResponseWriter{httptest.NewRecorder()}, req)
+
+	_, ok := w.(http.Flusher)
+	require.True(t, ok)
+	_, ok = w.(http.Hijacker)
+	require.True(t, ok)
+	_, ok = w.(http.CloseNotifier)
+	require.True(t, ok)
+	_, ok = w.(http.Pusher)
+	require.True(t, ok)
+	_, ok = w.(io.ReaderFrom)
+	require.True(t, ok)
+}
+
+func TestLogger_WrapResponse_PreservesFlusherOnly(t *testing.T) {
+	l := New(new(MockFieldLogger), Config{})
+	req := httptest.NewRequest("GET", "/", nil)
+
+	w := l.WrapResponse(struct {
+		http.ResponseWriter
+		http.Flusher
+	}{httptest.NewRecorder(), fullResponseWriter{}}, req)
+
+	_, ok := w.(http.Flusher)
+	require.True(t, ok)
+	_, ok = w.(http.Hijacker)
+	require.False(t, ok)
+}